@@ -4,6 +4,7 @@
 package cli
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -14,12 +15,16 @@ import (
 	"strings"
 
 	"github.com/aws/amazon-ecs-cli-v2/internal/pkg/archer"
-	"github.com/aws/amazon-ecs-cli-v2/internal/pkg/deploy"
 	"github.com/aws/amazon-ecs-cli-v2/internal/pkg/deploy/cloudformation"
+	"github.com/aws/amazon-ecs-cli-v2/internal/pkg/deploy/cloudformation/packager"
 	"github.com/aws/amazon-ecs-cli-v2/internal/pkg/manifest"
 	"github.com/aws/amazon-ecs-cli-v2/internal/pkg/store/ssm"
 	"github.com/aws/amazon-ecs-cli-v2/internal/pkg/term/prompt"
 	"github.com/aws/amazon-ecs-cli-v2/internal/pkg/workspace"
+	"github.com/aws/aws-sdk-go/aws/session"
+	awscfn "github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/aws/aws-sdk-go/service/cloudformation/cloudformationiface"
+	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -30,13 +35,27 @@ const (
 	appPackageEnvNamePrompt = "Which environment would you like to create this stack for?"
 )
 
+// Supported values for PackageAppOpts.Format.
+const (
+	appPackageFormatYAML   = "yaml"
+	appPackageFormatJSON   = "json"
+	appPackageFormatCDKOut = "cdk-out"
+)
+
 // PackageAppOpts holds the configuration needed to transform an application's manifest to CloudFormation.
 type PackageAppOpts struct {
 	// Fields with matching flags.
-	AppName   string
-	EnvName   string
-	Tag       string
-	OutputDir string
+	AppName            string
+	EnvName            string
+	Tag                string
+	OutputDir          string
+	S3Bucket           string
+	S3Prefix           string
+	Overrides          map[string]string
+	ExtraTags          map[string]string
+	Format             string
+	SkipValidate       bool
+	AddonsTemplatePath string
 
 	// Interfaces to interact with dependencies.
 	ws             archer.Workspace
@@ -45,6 +64,8 @@ type PackageAppOpts struct {
 	paramsWriter   io.Writer
 	fs             afero.Fs
 	prompt         prompter
+	packager       packager.Packager
+	cfnClient      cloudformationiface.CloudFormationAPI
 
 	globalOpts // Embed global options.
 }
@@ -134,20 +155,75 @@ func (opts *PackageAppOpts) Execute() error {
 		return err
 	}
 
-	if opts.OutputDir != "" {
-		if err := opts.setFileWriters(); err != nil {
+	tpl, params, err := opts.getTemplates(env)
+	if err != nil {
+		return err
+	}
+	if opts.S3Bucket != "" {
+		tpl, err = opts.packageLocalArtifacts(tpl)
+		if err != nil {
 			return err
 		}
 	}
+	if !opts.SkipValidate {
+		if err := opts.validateTemplate(tpl); err != nil {
+			return err
+		}
+	}
+	return opts.writeOutputs(tpl, params)
+}
 
-	tpl, params, err := opts.getTemplates(env)
+// packageLocalArtifacts uploads any local file artifacts referenced by tpl to opts.S3Bucket
+// and returns tpl with those references rewritten to their uploaded s3://bucket/key locations.
+func (opts *PackageAppOpts) packageLocalArtifacts(tpl string) (string, error) {
+	workDir, err := os.Getwd()
 	if err != nil {
-		return err
+		return "", fmt.Errorf("get working directory: %w", err)
+	}
+	rewritten, _, err := opts.packager.Package(context.Background(), tpl, workDir)
+	if err != nil {
+		return "", fmt.Errorf("package local artifacts for application %s: %w", opts.AppName, err)
+	}
+	return rewritten, nil
+}
+
+// writeOutputs converts tpl and params to opts.Format and writes them to opts.templateWriter
+// and opts.paramsWriter, creating opts.OutputDir first if one was set. For the cdk-out format it
+// instead writes tpl as a cloud assembly into opts.OutputDir; params are not written.
+func (opts *PackageAppOpts) writeOutputs(tpl, params string) error {
+	if opts.Format == appPackageFormatCDKOut {
+		if opts.OutputDir == "" {
+			return fmt.Errorf("--output-dir is required when --format is %s", appPackageFormatCDKOut)
+		}
+		return opts.writeCDKOut(tpl)
+	}
+
+	switch opts.Format {
+	case "", appPackageFormatYAML:
+		// tpl and params are already YAML.
+	case appPackageFormatJSON:
+		jsonTpl, err := yamlStringToJSON(tpl)
+		if err != nil {
+			return fmt.Errorf("convert CloudFormation template to JSON: %w", err)
+		}
+		jsonParams, err := yamlStringToJSON(params)
+		if err != nil {
+			return fmt.Errorf("convert stack configuration to JSON: %w", err)
+		}
+		tpl, params = jsonTpl, jsonParams
+	default:
+		return fmt.Errorf("unrecognized format %q: must be one of yaml, json, cdk-out", opts.Format)
+	}
+
+	if opts.OutputDir != "" {
+		if err := opts.setFileWriters(); err != nil {
+			return err
+		}
 	}
-	if _, err = opts.templateWriter.Write([]byte(tpl)); err != nil {
+	if _, err := opts.templateWriter.Write([]byte(tpl)); err != nil {
 		return err
 	}
-	_, err = opts.paramsWriter.Write([]byte(params))
+	_, err := opts.paramsWriter.Write([]byte(params))
 	return err
 }
 
@@ -169,22 +245,16 @@ func (opts *PackageAppOpts) getTemplates(env *archer.Environment) (string, strin
 	if err != nil {
 		return "", "", err
 	}
-	switch t := mft.(type) {
-	case *manifest.LBFargateManifest:
-		stack := cloudformation.NewLBFargateStack(&deploy.CreateLBFargateAppInput{
-			App:      mft.(*manifest.LBFargateManifest),
-			Env:      env,
-			ImageTag: opts.Tag,
-		})
-		tpl, err := stack.Template()
-		if err != nil {
-			return "", "", err
-		}
-		params, err := stack.SerializedParameters()
-		return tpl, params, err
-	default:
-		return "", "", fmt.Errorf("create CloudFormation template for manifest of type %T", t)
+	stack, err := cloudformation.RenderStack(mft, env, opts.Tag, opts.Overrides, opts.ExtraTags, opts.AddonsTemplatePath)
+	if err != nil {
+		return "", "", err
 	}
+	tpl, err := stack.Template()
+	if err != nil {
+		return "", "", err
+	}
+	params, err := stack.SerializedParameters()
+	return tpl, params, err
 }
 
 // setFileWriters creates the output directory, and updates the template and param writers to file writers in the directory.
@@ -193,7 +263,11 @@ func (opts *PackageAppOpts) setFileWriters() error {
 		return fmt.Errorf("create directory %s: %w", opts.OutputDir, err)
 	}
 
-	templatePath := filepath.Join(opts.OutputDir, fmt.Sprintf("%s.stack.yml", opts.AppName))
+	templateExt := "yml"
+	if opts.Format == appPackageFormatJSON {
+		templateExt = "json"
+	}
+	templatePath := filepath.Join(opts.OutputDir, fmt.Sprintf("%s.stack.%s", opts.AppName, templateExt))
 	templateFile, err := opts.fs.Create(templatePath)
 	if err != nil {
 		return fmt.Errorf("create file %s: %w", templatePath, err)
@@ -258,6 +332,19 @@ func BuildAppPackageCmd() *cobra.Command {
 				return fmt.Errorf("couldn't connect to application datastore: %w", err)
 			}
 			opts.envStore = store
+
+			if opts.S3Bucket != "" || !opts.SkipValidate {
+				sess, err := session.NewSession()
+				if err != nil {
+					return fmt.Errorf("create session: %w", err)
+				}
+				if opts.S3Bucket != "" {
+					opts.packager = packager.NewS3Packager(s3.New(sess), opts.S3Bucket, opts.S3Prefix)
+				}
+				if !opts.SkipValidate {
+					opts.cfnClient = awscfn.New(sess)
+				}
+			}
 			return opts.Validate()
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -275,5 +362,12 @@ func BuildAppPackageCmd() *cobra.Command {
 	cmd.Flags().StringVarP(&opts.EnvName, "env", "e", opts.EnvName, "Name of the environment.")
 	cmd.Flags().StringVar(&opts.Tag, "tag", opts.Tag, `Optional. The application's image tag. Defaults to your latest git commit's hash.`)
 	cmd.Flags().StringVar(&opts.OutputDir, "output-dir", opts.OutputDir, "Optional. Writes the stack template and template configuration to a directory.")
+	cmd.Flags().StringVar(&opts.S3Bucket, "s3-bucket", opts.S3Bucket, "Optional. Uploads local file artifacts referenced by the manifest to this S3 bucket, and rewrites the template to reference the uploaded objects.")
+	cmd.Flags().StringVar(&opts.S3Prefix, "s3-prefix", opts.S3Prefix, "Optional. Key prefix under which uploaded local file artifacts are stored in the S3 bucket.")
+	cmd.Flags().StringToStringVar(&opts.Overrides, "parameter-overrides", nil, "Optional. Overrides template parameter values, as KEY=VALUE pairs. Can be specified multiple times.")
+	cmd.Flags().StringToStringVar(&opts.ExtraTags, "tags", nil, "Optional. Adds additional tags to the CloudFormation stack, as KEY=VALUE pairs. Can be specified multiple times.")
+	cmd.Flags().StringVar(&opts.Format, "format", appPackageFormatYAML, `Optional. Output format of the template and configuration: "yaml", "json", or "cdk-out" (requires --output-dir).`)
+	cmd.Flags().BoolVar(&opts.SkipValidate, "skip-validate", true, "Optional. Skips validating the rendered template against CloudFormation before writing it. Defaults to true so `package` stays a local, offline render; pass --skip-validate=false to validate against the live CloudFormation API (requires AWS credentials).")
+	cmd.Flags().StringVar(&opts.AddonsTemplatePath, "addons-template", "", "Optional. Path, relative to the workspace root, to a CloudFormation template of additional resources to deploy alongside the application as a nested stack.")
 	return cmd
 }
\ No newline at end of file
@@ -0,0 +1,118 @@
+// Copyright 2019 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/afero"
+	"gopkg.in/yaml.v2"
+)
+
+// cdkCloudAssemblyVersion is the "version" field written to manifest.json and the assets
+// manifest, matching the schema version emitted by the AWS CDK CLI at the time of writing.
+const cdkCloudAssemblyVersion = "17.0.0"
+
+// cdkManifest is the minimal subset of a CDK cloud assembly's manifest.json that's needed
+// for downstream tooling to locate a stack's template.
+type cdkManifest struct {
+	Version   string                 `json:"version"`
+	Artifacts map[string]cdkArtifact `json:"artifacts"`
+}
+
+type cdkArtifact struct {
+	Type       string                `json:"type"`
+	Properties cdkArtifactProperties `json:"properties"`
+}
+
+type cdkArtifactProperties struct {
+	TemplateFile string `json:"templateFile"`
+}
+
+// cdkAssetsManifest is the minimal subset of a CDK cloud assembly's per-stack assets.json.
+// archer apps don't currently publish file or Docker image assets outside of the rendered
+// template, so Files is always empty; Docker image assets are not listed.
+type cdkAssetsManifest struct {
+	Version string                 `json:"version"`
+	Files   map[string]interface{} `json:"files"`
+}
+
+// writeCDKOut writes tpl and a minimal cloud assembly (manifest.json + <AppName>.template.json +
+// <AppName>-<EnvName>.assets.json) to opts.OutputDir, so tooling that consumes CDK cloud assembly
+// output can pick up archer-generated apps.
+func (opts *PackageAppOpts) writeCDKOut(tpl string) error {
+	if err := opts.fs.MkdirAll(opts.OutputDir, 0755); err != nil {
+		return fmt.Errorf("create directory %s: %w", opts.OutputDir, err)
+	}
+
+	jsonTpl, err := yamlStringToJSON(tpl)
+	if err != nil {
+		return fmt.Errorf("convert CloudFormation template to JSON: %w", err)
+	}
+	templateFileName := fmt.Sprintf("%s.template.json", opts.AppName)
+	if err := afero.WriteFile(opts.fs, filepath.Join(opts.OutputDir, templateFileName), []byte(jsonTpl), 0644); err != nil {
+		return fmt.Errorf("write %s: %w", templateFileName, err)
+	}
+
+	assets, err := json.MarshalIndent(cdkAssetsManifest{
+		Version: cdkCloudAssemblyVersion,
+		Files:   map[string]interface{}{},
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal assets manifest: %w", err)
+	}
+	assetsFileName := fmt.Sprintf("%s-%s.assets.json", opts.AppName, opts.EnvName)
+	if err := afero.WriteFile(opts.fs, filepath.Join(opts.OutputDir, assetsFileName), assets, 0644); err != nil {
+		return fmt.Errorf("write %s: %w", assetsFileName, err)
+	}
+
+	manifest, err := json.MarshalIndent(cdkManifest{
+		Version: cdkCloudAssemblyVersion,
+		Artifacts: map[string]cdkArtifact{
+			opts.AppName: {
+				Type:       "aws:cloudformation:stack",
+				Properties: cdkArtifactProperties{TemplateFile: templateFileName},
+			},
+		},
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal manifest.json: %w", err)
+	}
+	return afero.WriteFile(opts.fs, filepath.Join(opts.OutputDir, "manifest.json"), manifest, 0644)
+}
+
+// yamlStringToJSON converts a YAML document to its equivalent JSON representation.
+func yamlStringToJSON(in string) (string, error) {
+	var v interface{}
+	if err := yaml.Unmarshal([]byte(in), &v); err != nil {
+		return "", fmt.Errorf("unmarshal YAML: %w", err)
+	}
+	out, err := json.MarshalIndent(stringifyMapKeys(v), "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshal JSON: %w", err)
+	}
+	return string(out), nil
+}
+
+// stringifyMapKeys recursively converts the map[interface{}]interface{} values produced by
+// gopkg.in/yaml.v2 into map[string]interface{}, which encoding/json can marshal.
+func stringifyMapKeys(in interface{}) interface{} {
+	switch v := in.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, val := range v {
+			m[fmt.Sprintf("%v", key)] = stringifyMapKeys(val)
+		}
+		return m
+	case []interface{}:
+		for i, val := range v {
+			v[i] = stringifyMapKeys(val)
+		}
+		return v
+	default:
+		return v
+	}
+}
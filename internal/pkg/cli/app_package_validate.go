@@ -0,0 +1,84 @@
+// Copyright 2019 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	awscfn "github.com/aws/aws-sdk-go/service/cloudformation"
+)
+
+// validateTemplate calls the CloudFormation ValidateTemplate API so that malformed templates are
+// caught before a deploy round-trip, returning an ErrTemplateValidation that callers can inspect
+// for the offending resources instead of a single flattened message.
+func (opts *PackageAppOpts) validateTemplate(tpl string) error {
+	if _, err := opts.cfnClient.ValidateTemplate(&awscfn.ValidateTemplateInput{
+		TemplateBody: aws.String(tpl),
+	}); err != nil {
+		awsErr, ok := err.(awserr.Error)
+		if !ok {
+			return fmt.Errorf("validate CloudFormation template for application %s: %w", opts.AppName, err)
+		}
+		return &ErrTemplateValidation{
+			appName:   opts.AppName,
+			awsErr:    awsErr,
+			resources: parseValidationResources(awsErr.Message()),
+		}
+	}
+	return nil
+}
+
+// ErrTemplateValidation is returned by validateTemplate when CloudFormation rejects the rendered
+// template, so that a caller can list which resources it complained about instead of only being
+// able to print a single flattened message.
+type ErrTemplateValidation struct {
+	appName   string
+	awsErr    awserr.Error
+	resources []string
+}
+
+func (e *ErrTemplateValidation) Error() string {
+	msg := fmt.Sprintf("validate CloudFormation template for application %s: %s: %s", e.appName, e.awsErr.Code(), e.awsErr.Message())
+	if len(e.resources) == 0 {
+		return msg
+	}
+	return fmt.Sprintf("%s (resources: %s)", msg, strings.Join(e.resources, ", "))
+}
+
+// Unwrap returns the underlying CloudFormation API error.
+func (e *ErrTemplateValidation) Unwrap() error {
+	return e.awsErr
+}
+
+// Resources returns the logical IDs of the resources CloudFormation's validation message named,
+// if any could be parsed out of it. It's empty when the message doesn't reference resources by
+// name, e.g. a template-wide syntax error.
+func (e *ErrTemplateValidation) Resources() []string {
+	return e.resources
+}
+
+// unresolvedResourceDependenciesPattern matches the resource names CloudFormation lists when
+// ValidateTemplate rejects a template for referencing a resource that doesn't exist, e.g.
+// `Template format error: Unresolved resource dependencies [TargetGroup] in the Resources block`.
+var unresolvedResourceDependenciesPattern = regexp.MustCompile(`resource dependencies \[([^\]]+)\]`)
+
+// parseValidationResources best-effort parses the logical IDs of the offending resources out of
+// a CloudFormation ValidateTemplate error message. CloudFormation doesn't return resource names
+// in a structured field, so this only recognizes the message shapes it's known to produce; it
+// returns nil for messages it doesn't recognize, e.g. a template-wide syntax error.
+func parseValidationResources(message string) []string {
+	match := unresolvedResourceDependenciesPattern.FindStringSubmatch(message)
+	if match == nil {
+		return nil
+	}
+	var resources []string
+	for _, name := range strings.Split(match[1], ",") {
+		resources = append(resources, strings.TrimSpace(name))
+	}
+	return resources
+}
@@ -0,0 +1,76 @@
+// Copyright 2019 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package cfn provides typed Go representations of the CloudFormation resources this
+// module emits, modeled after go-cloudformation/goformation. Stacks build a Template as
+// a Go value and marshal it to YAML, rather than executing text/template over a raw YAML
+// file, so template construction can be unit-tested and missing fields fail at compile
+// time instead of rendering as empty strings.
+package cfn
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Template is an in-memory CloudFormation template.
+type Template struct {
+	AWSTemplateFormatVersion string               `yaml:"AWSTemplateFormatVersion"`
+	Description              string               `yaml:"Description,omitempty"`
+	Parameters               map[string]Parameter `yaml:"Parameters,omitempty"`
+	Resources                map[string]*Resource `yaml:"Resources"`
+	Outputs                  map[string]Output    `yaml:"Outputs,omitempty"`
+}
+
+// NewTemplate returns an empty Template with its Resources map initialized.
+func NewTemplate() *Template {
+	return &Template{
+		AWSTemplateFormatVersion: "2010-09-09",
+		Resources:                make(map[string]*Resource),
+	}
+}
+
+// AddResource adds resource to the template under logicalID, overwriting any existing
+// resource registered under the same name.
+func (t *Template) AddResource(logicalID string, resource *Resource) {
+	t.Resources[logicalID] = resource
+}
+
+// YAML marshals the template to its CloudFormation YAML representation.
+func (t *Template) YAML() (string, error) {
+	out, err := yaml.Marshal(t)
+	if err != nil {
+		return "", fmt.Errorf("marshal CloudFormation template: %w", err)
+	}
+	return string(out), nil
+}
+
+// Resource is a single entry under a template's Resources section: its CloudFormation
+// Type plus the typed struct describing its Properties.
+type Resource struct {
+	Type       string      `yaml:"Type"`
+	DependsOn  []string    `yaml:"DependsOn,omitempty"`
+	Condition  string      `yaml:"Condition,omitempty"`
+	Properties interface{} `yaml:"Properties,omitempty"`
+}
+
+// Parameter is an entry under a template's Parameters section.
+type Parameter struct {
+	Type           string `yaml:"Type"`
+	Default        string `yaml:"Default,omitempty"`
+	AllowedPattern string `yaml:"AllowedPattern,omitempty"`
+	Description    string `yaml:"Description,omitempty"`
+}
+
+// Output is an entry under a template's Outputs section.
+type Output struct {
+	Value     interface{} `yaml:"Value"`
+	Export    *Export     `yaml:"Export,omitempty"`
+	Condition string      `yaml:"Condition,omitempty"`
+}
+
+// Export names an Output so other stacks can import it with Fn::ImportValue.
+type Export struct {
+	Name interface{} `yaml:"Name"`
+}
@@ -0,0 +1,95 @@
+// Copyright 2019 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package cfn
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v2"
+)
+
+func TestTemplate_YAML(t *testing.T) {
+	testCases := map[string]struct {
+		tpl     *Template
+		wantErr bool
+		check   func(t *testing.T, out string)
+	}{
+		"renders a resource's type and properties": {
+			tpl: func() *Template {
+				tpl := NewTemplate()
+				tpl.AddResource("Bucket", NewNestedStack(NestedStackProperties{
+					TemplateURL: "Local://addons.yml",
+				}))
+				return tpl
+			}(),
+			check: func(t *testing.T, out string) {
+				var decoded struct {
+					Resources map[string]struct {
+						Type       string
+						Properties struct {
+							TemplateURL string `yaml:"TemplateURL"`
+						}
+					}
+				}
+				if err := yaml.Unmarshal([]byte(out), &decoded); err != nil {
+					t.Fatalf("unmarshal rendered template: %v", err)
+				}
+				bucket, ok := decoded.Resources["Bucket"]
+				if !ok {
+					t.Fatalf("expected a Bucket resource, got %+v", decoded.Resources)
+				}
+				if bucket.Type != cloudformationStackType {
+					t.Errorf("Type = %q, want %q", bucket.Type, cloudformationStackType)
+				}
+				if bucket.Properties.TemplateURL != "Local://addons.yml" {
+					t.Errorf("TemplateURL = %q, want %q", bucket.Properties.TemplateURL, "Local://addons.yml")
+				}
+			},
+		},
+		"AddResource overwrites an existing logical ID": {
+			tpl: func() *Template {
+				tpl := NewTemplate()
+				tpl.AddResource("Bucket", NewNestedStack(NestedStackProperties{TemplateURL: "Local://first.yml"}))
+				tpl.AddResource("Bucket", NewNestedStack(NestedStackProperties{TemplateURL: "Local://second.yml"}))
+				return tpl
+			}(),
+			check: func(t *testing.T, out string) {
+				if strings.Count(out, "Bucket:") != 1 {
+					t.Fatalf("expected exactly one Bucket entry, got:\n%s", out)
+				}
+				if !strings.Contains(out, "Local://second.yml") {
+					t.Errorf("expected the second AddResource call to win, got:\n%s", out)
+				}
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			out, err := tc.tpl.YAML()
+			if tc.wantErr {
+				if err == nil {
+					t.Fatal("expected an error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			tc.check(t, out)
+		})
+	}
+}
+
+func TestTemplate_YAML_templateFormatVersion(t *testing.T) {
+	tpl := NewTemplate()
+	out, err := tpl.YAML()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "AWSTemplateFormatVersion: \"2010-09-09\"") && !strings.Contains(out, "AWSTemplateFormatVersion: 2010-09-09") {
+		t.Errorf("expected the rendered template to pin AWSTemplateFormatVersion, got:\n%s", out)
+	}
+}
@@ -0,0 +1,19 @@
+// Copyright 2019 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package cfn
+
+const cloudformationStackType = "AWS::CloudFormation::Stack"
+
+// NewNestedStack returns an "AWS::CloudFormation::Stack" resource with the given properties.
+func NewNestedStack(props NestedStackProperties) *Resource {
+	return &Resource{
+		Type:       cloudformationStackType,
+		Properties: props,
+	}
+}
+
+// NestedStackProperties are the properties of an "AWS::CloudFormation::Stack" resource.
+type NestedStackProperties struct {
+	TemplateURL interface{} `yaml:"TemplateURL"`
+}
@@ -0,0 +1,102 @@
+// Copyright 2019 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package cfn
+
+const (
+	ecsTaskDefinitionType = "AWS::ECS::TaskDefinition"
+	ecsServiceType        = "AWS::ECS::Service"
+)
+
+// NewECSTaskDefinition returns an "AWS::ECS::TaskDefinition" resource with the given properties.
+func NewECSTaskDefinition(props ECSTaskDefinitionProperties) *Resource {
+	return &Resource{
+		Type:       ecsTaskDefinitionType,
+		Properties: props,
+	}
+}
+
+// ECSTaskDefinitionProperties are the properties of an "AWS::ECS::TaskDefinition" resource.
+type ECSTaskDefinitionProperties struct {
+	Family                  string                `yaml:"Family"`
+	ContainerDefinitions    []ContainerDefinition `yaml:"ContainerDefinitions"`
+	Cpu                     interface{}           `yaml:"Cpu"`
+	Memory                  interface{}           `yaml:"Memory"`
+	NetworkMode             string                `yaml:"NetworkMode"`
+	RequiresCompatibilities []string              `yaml:"RequiresCompatibilities"`
+	ExecutionRoleArn        interface{}            `yaml:"ExecutionRoleArn"`
+	TaskRoleArn             interface{}            `yaml:"TaskRoleArn"`
+}
+
+// ContainerDefinition describes a single container within a task definition.
+type ContainerDefinition struct {
+	Name             string            `yaml:"Name"`
+	Image            interface{}       `yaml:"Image"`
+	Essential        bool              `yaml:"Essential"`
+	PortMappings     []PortMapping     `yaml:"PortMappings,omitempty"`
+	Environment      []KeyValuePair    `yaml:"Environment,omitempty"`
+	Secrets          []KeyValuePair    `yaml:"Secrets,omitempty"`
+	LogConfiguration *LogConfiguration `yaml:"LogConfiguration,omitempty"`
+}
+
+// PortMapping maps a container port to the host.
+type PortMapping struct {
+	ContainerPort interface{} `yaml:"ContainerPort"`
+}
+
+// KeyValuePair is a name/value pair, used for container environment variables and secrets.
+type KeyValuePair struct {
+	Name  string      `yaml:"Name"`
+	Value interface{} `yaml:"Value"`
+}
+
+// LogConfiguration configures the log driver for a container.
+type LogConfiguration struct {
+	LogDriver string                 `yaml:"LogDriver"`
+	Options   map[string]interface{} `yaml:"Options,omitempty"`
+}
+
+// NewECSService returns an "AWS::ECS::Service" resource with the given properties.
+func NewECSService(props ECSServiceProperties) *Resource {
+	return &Resource{
+		Type:       ecsServiceType,
+		Properties: props,
+	}
+}
+
+// ECSServiceProperties are the properties of an "AWS::ECS::Service" resource.
+type ECSServiceProperties struct {
+	Cluster                 interface{}              `yaml:"Cluster"`
+	ServiceName             string                   `yaml:"ServiceName,omitempty"`
+	TaskDefinition          interface{}              `yaml:"TaskDefinition"`
+	DesiredCount            interface{}              `yaml:"DesiredCount"`
+	LaunchType              string                   `yaml:"LaunchType"`
+	NetworkConfiguration    *NetworkConfiguration    `yaml:"NetworkConfiguration,omitempty"`
+	LoadBalancers           []LoadBalancer           `yaml:"LoadBalancers,omitempty"`
+	DeploymentConfiguration *DeploymentConfiguration `yaml:"DeploymentConfiguration,omitempty"`
+}
+
+// NetworkConfiguration configures the awsvpc networking mode for a service.
+type NetworkConfiguration struct {
+	AwsVpcConfiguration AwsVpcConfiguration `yaml:"AwsvpcConfiguration"`
+}
+
+// AwsVpcConfiguration lists the subnets and security groups a service's tasks run in.
+type AwsVpcConfiguration struct {
+	Subnets        interface{} `yaml:"Subnets"`
+	SecurityGroups interface{} `yaml:"SecurityGroups"`
+	AssignPublicIp string      `yaml:"AssignPublicIp,omitempty"`
+}
+
+// LoadBalancer associates a service's container port with a target group.
+type LoadBalancer struct {
+	ContainerName  string      `yaml:"ContainerName"`
+	ContainerPort  interface{} `yaml:"ContainerPort"`
+	TargetGroupArn interface{} `yaml:"TargetGroupArn"`
+}
+
+// DeploymentConfiguration bounds how a service's tasks are replaced during deployments.
+type DeploymentConfiguration struct {
+	MaximumPercent        interface{} `yaml:"MaximumPercent,omitempty"`
+	MinimumHealthyPercent interface{} `yaml:"MinimumHealthyPercent,omitempty"`
+}
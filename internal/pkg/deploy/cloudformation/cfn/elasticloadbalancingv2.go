@@ -0,0 +1,54 @@
+// Copyright 2019 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package cfn
+
+const (
+	elbListenerRuleType = "AWS::ElasticLoadBalancingV2::ListenerRule"
+	elbTargetGroupType  = "AWS::ElasticLoadBalancingV2::TargetGroup"
+)
+
+// NewListenerRule returns an "AWS::ElasticLoadBalancingV2::ListenerRule" resource with the given properties.
+func NewListenerRule(props ListenerRuleProperties) *Resource {
+	return &Resource{
+		Type:       elbListenerRuleType,
+		Properties: props,
+	}
+}
+
+// ListenerRuleProperties are the properties of an "AWS::ElasticLoadBalancingV2::ListenerRule" resource.
+type ListenerRuleProperties struct {
+	ListenerArn interface{}     `yaml:"ListenerArn"`
+	Priority    interface{}     `yaml:"Priority"`
+	Actions     []RuleAction    `yaml:"Actions"`
+	Conditions  []RuleCondition `yaml:"Conditions"`
+}
+
+// RuleAction is an action taken by a listener rule, e.g. forwarding to a target group.
+type RuleAction struct {
+	Type           string      `yaml:"Type"`
+	TargetGroupArn interface{} `yaml:"TargetGroupArn"`
+}
+
+// RuleCondition is a single condition a request must match for a listener rule to apply.
+type RuleCondition struct {
+	Field  string        `yaml:"Field"`
+	Values []interface{} `yaml:"Values"`
+}
+
+// NewTargetGroup returns an "AWS::ElasticLoadBalancingV2::TargetGroup" resource with the given properties.
+func NewTargetGroup(props TargetGroupProperties) *Resource {
+	return &Resource{
+		Type:       elbTargetGroupType,
+		Properties: props,
+	}
+}
+
+// TargetGroupProperties are the properties of an "AWS::ElasticLoadBalancingV2::TargetGroup" resource.
+type TargetGroupProperties struct {
+	Port            interface{} `yaml:"Port"`
+	Protocol        string      `yaml:"Protocol"`
+	TargetType      string      `yaml:"TargetType"`
+	VpcId           interface{} `yaml:"VpcId"`
+	HealthCheckPath string      `yaml:"HealthCheckPath,omitempty"`
+}
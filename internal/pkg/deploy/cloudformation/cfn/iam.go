@@ -0,0 +1,43 @@
+// Copyright 2019 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package cfn
+
+const iamRoleType = "AWS::IAM::Role"
+
+// NewIAMRole returns an "AWS::IAM::Role" resource with the given properties.
+func NewIAMRole(props IAMRoleProperties) *Resource {
+	return &Resource{
+		Type:       iamRoleType,
+		Properties: props,
+	}
+}
+
+// IAMRoleProperties are the properties of an "AWS::IAM::Role" resource.
+type IAMRoleProperties struct {
+	AssumeRolePolicyDocument interface{} `yaml:"AssumeRolePolicyDocument"`
+	ManagedPolicyArns        []string    `yaml:"ManagedPolicyArns,omitempty"`
+	Policies                 []IAMPolicy `yaml:"Policies,omitempty"`
+}
+
+// IAMPolicy is an inline policy attached to a role.
+type IAMPolicy struct {
+	PolicyName     string      `yaml:"PolicyName"`
+	PolicyDocument interface{} `yaml:"PolicyDocument"`
+}
+
+// AssumeRolePolicyDocument builds the standard trust policy granting service to assume the role.
+func AssumeRolePolicyDocument(service string) map[string]interface{} {
+	return map[string]interface{}{
+		"Version": "2012-10-17",
+		"Statement": []map[string]interface{}{
+			{
+				"Effect": "Allow",
+				"Principal": map[string]string{
+					"Service": service,
+				},
+				"Action": "sts:AssumeRole",
+			},
+		},
+	}
+}
@@ -0,0 +1,38 @@
+// Copyright 2019 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package cfn
+
+import "fmt"
+
+// Ref returns the CloudFormation intrinsic function { "Ref": logicalID }.
+func Ref(logicalID string) map[string]string {
+	return map[string]string{"Ref": logicalID}
+}
+
+// GetAtt returns the CloudFormation intrinsic function
+// { "Fn::GetAtt": "logicalID.attribute" }.
+func GetAtt(logicalID, attribute string) map[string]string {
+	return map[string]string{"Fn::GetAtt": fmt.Sprintf("%s.%s", logicalID, attribute)}
+}
+
+// Sub returns the CloudFormation intrinsic function { "Fn::Sub": expression }.
+func Sub(expression string) map[string]string {
+	return map[string]string{"Fn::Sub": expression}
+}
+
+// Join returns the CloudFormation intrinsic function
+// { "Fn::Join": [delimiter, values] }.
+func Join(delimiter string, values ...interface{}) map[string]interface{} {
+	return map[string]interface{}{"Fn::Join": []interface{}{delimiter, values}}
+}
+
+// ImportValue returns the CloudFormation intrinsic function { "Fn::ImportValue": name }.
+func ImportValue(name interface{}) map[string]interface{} {
+	return map[string]interface{}{"Fn::ImportValue": name}
+}
+
+// Split returns the CloudFormation intrinsic function { "Fn::Split": [delimiter, source] }.
+func Split(delimiter string, source interface{}) map[string]interface{} {
+	return map[string]interface{}{"Fn::Split": []interface{}{delimiter, source}}
+}
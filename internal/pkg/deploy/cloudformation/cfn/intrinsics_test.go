@@ -0,0 +1,49 @@
+// Copyright 2019 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package cfn
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestIntrinsics(t *testing.T) {
+	testCases := map[string]struct {
+		got  interface{}
+		want interface{}
+	}{
+		"Ref": {
+			got:  Ref("TargetGroup"),
+			want: map[string]string{"Ref": "TargetGroup"},
+		},
+		"GetAtt": {
+			got:  GetAtt("LoadBalancer", "DNSName"),
+			want: map[string]string{"Fn::GetAtt": "LoadBalancer.DNSName"},
+		},
+		"Sub": {
+			got:  Sub("${RulePath}*"),
+			want: map[string]string{"Fn::Sub": "${RulePath}*"},
+		},
+		"Join": {
+			got:  Join("/", "a", "b"),
+			want: map[string]interface{}{"Fn::Join": []interface{}{"/", []interface{}{"a", "b"}}},
+		},
+		"ImportValue": {
+			got:  ImportValue("Infra-VpcId"),
+			want: map[string]interface{}{"Fn::ImportValue": "Infra-VpcId"},
+		},
+		"Split": {
+			got:  Split(",", "a,b,c"),
+			want: map[string]interface{}{"Fn::Split": []interface{}{",", "a,b,c"}},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if !reflect.DeepEqual(tc.got, tc.want) {
+				t.Errorf("got %#v, want %#v", tc.got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,20 @@
+// Copyright 2019 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package cfn
+
+const logsLogGroupType = "AWS::Logs::LogGroup"
+
+// NewLogGroup returns an "AWS::Logs::LogGroup" resource with the given properties.
+func NewLogGroup(props LogGroupProperties) *Resource {
+	return &Resource{
+		Type:       logsLogGroupType,
+		Properties: props,
+	}
+}
+
+// LogGroupProperties are the properties of an "AWS::Logs::LogGroup" resource.
+type LogGroupProperties struct {
+	LogGroupName    interface{} `yaml:"LogGroupName,omitempty"`
+	RetentionInDays interface{} `yaml:"RetentionInDays,omitempty"`
+}
@@ -6,20 +6,23 @@ package cloudformation
 import (
 	"bytes"
 	"fmt"
+	"sort"
 	"strconv"
+	"strings"
 	"text/template"
 
 	"github.com/aws/amazon-ecs-cli-v2/internal/pkg/deploy"
+	"github.com/aws/amazon-ecs-cli-v2/internal/pkg/deploy/cloudformation/cfn"
 	"github.com/aws/amazon-ecs-cli-v2/internal/pkg/manifest"
 	"github.com/aws/amazon-ecs-cli-v2/templates"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/cloudformation"
 	"github.com/gobuffalo/packd"
+	"gopkg.in/yaml.v2"
 )
 
 const (
-	lbFargateAppTemplatePath = "lb-fargate-service/cf.yml"
-	lbFargateAppParamsPath   = "lb-fargate-service/params.json"
+	lbFargateAppParamsPath = "lb-fargate-service/params.json"
 )
 
 const (
@@ -35,20 +38,81 @@ const (
 	lbFargateTaskCountKey           = "TaskCount"
 )
 
+// reservedLBFargateParamKeys are template parameters whose values are derived from the
+// application and environment being packaged, and so cannot be overridden by the caller.
+var reservedLBFargateParamKeys = map[string]bool{
+	lbFargateParamProjectNameKey:    true,
+	lbFargateParamEnvNameKey:        true,
+	lbFargateParamAppNameKey:        true,
+	lbFargateParamContainerImageKey: true,
+}
+
 // LBFargateStackConfig represents the configuration needed to create a CloudFormation stack from a
 // load balanced Fargate application.
 type LBFargateStackConfig struct {
 	*deploy.CreateLBFargateAppInput
 
-	box packd.Box
+	box                packd.Box
+	overrides          map[string]string
+	extraTags          map[string]string
+	priorityAllocator  PriorityAllocator
+	listenerArn        string
+	addonsTemplatePath string
+}
+
+// LBFargateStackOption configures optional behavior of a LBFargateStackConfig.
+type LBFargateStackOption func(*LBFargateStackConfig)
+
+// WithParameterOverrides overrides the rendered value of the template parameters matching the
+// given keys. Reserved keys (ProjectName, EnvName, AppName, ContainerImage) are rejected when the
+// stack's parameters are computed.
+func WithParameterOverrides(overrides map[string]string) LBFargateStackOption {
+	return func(c *LBFargateStackConfig) {
+		c.overrides = overrides
+	}
+}
+
+// WithExtraTags adds additional tags to apply to the stack, alongside the default
+// project/env/app tags.
+func WithExtraTags(tags map[string]string) LBFargateStackOption {
+	return func(c *LBFargateStackConfig) {
+		c.extraTags = tags
+	}
+}
+
+// WithPriorityAllocator sets the allocator used to assign the ALB listener rule priority for the
+// application's routing rule, and the ARN of the shared listener it should be assigned on. When
+// no allocator is supplied, the stack falls back to a HashPriorityAllocator so that packaging
+// still produces a deterministic priority without needing to talk to AWS.
+func WithPriorityAllocator(allocator PriorityAllocator, listenerArn string) LBFargateStackOption {
+	return func(c *LBFargateStackConfig) {
+		c.priorityAllocator = allocator
+		c.listenerArn = listenerArn
+	}
+}
+
+// WithAddonsTemplatePath sets the path, relative to the workspace root, to an optional
+// CloudFormation template whose resources (e.g. an S3 bucket, a DynamoDB table) are deployed
+// alongside the app as a nested stack. When set, toCloudFormationTemplate adds a nested stack
+// resource referencing the template via a "Local://" marker, which PackageAppOpts.
+// packageLocalArtifacts (or, at deploy time, the equivalent step in `archer app deploy`) resolves
+// to an uploaded S3 object URL.
+func WithAddonsTemplatePath(path string) LBFargateStackOption {
+	return func(c *LBFargateStackConfig) {
+		c.addonsTemplatePath = path
+	}
 }
 
 // NewLBFargateStack creates a new LBFargateStackConfig from a load-balanced AWS Fargate application.
-func NewLBFargateStack(in *deploy.CreateLBFargateAppInput) *LBFargateStackConfig {
-	return &LBFargateStackConfig{
+func NewLBFargateStack(in *deploy.CreateLBFargateAppInput, opts ...LBFargateStackOption) *LBFargateStackConfig {
+	c := &LBFargateStackConfig{
 		CreateLBFargateAppInput: in,
 		box:                     templates.Box(),
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
 }
 
 // StackName returns the name of the stack.
@@ -64,24 +128,148 @@ func (c *LBFargateStackConfig) StackName() string {
 
 // Template returns the CloudFormation template for the application parametrized for the environment.
 func (c *LBFargateStackConfig) Template() (string, error) {
-	content, err := c.box.FindString(lbFargateAppTemplatePath)
+	tpl, err := c.toCloudFormationTemplate()
 	if err != nil {
-		return "", &ErrTemplateNotFound{templateLocation: lbFargateAppTemplatePath, parentErr: err}
+		return "", err
 	}
-	tpl, err := template.New("template").Parse(content)
+	out, err := tpl.YAML()
 	if err != nil {
-		return "", fmt.Errorf("parse CloudFormation template for %s: %w", c.App.Type, err)
+		return "", fmt.Errorf("marshal CloudFormation template for %s: %w", c.App.Type, err)
 	}
-	var buf bytes.Buffer
-	if err := tpl.Execute(&buf, c.toTemplateParams()); err != nil {
-		return "", fmt.Errorf("execute CloudFormation template for %s: %w", c.App.Type, err)
+	return out, nil
+}
+
+// toCloudFormationTemplate builds the in-memory CloudFormation template for the application,
+// parametrized for the environment.
+func (c *LBFargateStackConfig) toCloudFormationTemplate() (*cfn.Template, error) {
+	params, err := c.toTemplateParams()
+	if err != nil {
+		return nil, err
+	}
+	name := fmt.Sprintf("%s-%s-%s", params.Env.Project, params.Env.Name, params.App.Name)
+
+	tpl := cfn.NewTemplate()
+	tpl.Description = fmt.Sprintf("CloudFormation template for the %s application", params.App.Name)
+	tpl.Parameters = map[string]cfn.Parameter{
+		lbFargateParamProjectNameKey:    {Type: "String"},
+		lbFargateParamEnvNameKey:        {Type: "String"},
+		lbFargateParamAppNameKey:        {Type: "String"},
+		lbFargateParamContainerImageKey: {Type: "String"},
+		lbFargateParamContainerPortKey:  {Type: "Number"},
+		lbFargateRulePriorityKey:        {Type: "Number"},
+		lbFargateRulePathKey:            {Type: "String"},
+		lbFargateTaskCPUKey:             {Type: "String"},
+		lbFargateTaskMemoryKey:          {Type: "String"},
+		lbFargateTaskCountKey:           {Type: "Number"},
 	}
-	return buf.String(), nil
+
+	tpl.AddResource("ExecutionRole", cfn.NewIAMRole(cfn.IAMRoleProperties{
+		AssumeRolePolicyDocument: cfn.AssumeRolePolicyDocument("ecs-tasks.amazonaws.com"),
+		ManagedPolicyArns: []string{
+			"arn:aws:iam::aws:policy/service-role/AmazonECSTaskExecutionRolePolicy",
+		},
+	}))
+
+	tpl.AddResource("LogGroup", cfn.NewLogGroup(cfn.LogGroupProperties{
+		LogGroupName:    cfn.Sub(fmt.Sprintf("/ecs/%s", name)),
+		RetentionInDays: 30,
+	}))
+
+	tpl.AddResource("TaskDefinition", cfn.NewECSTaskDefinition(cfn.ECSTaskDefinitionProperties{
+		Family:                  name,
+		NetworkMode:             "awsvpc",
+		RequiresCompatibilities: []string{"FARGATE"},
+		Cpu:                     cfn.Ref(lbFargateTaskCPUKey),
+		Memory:                  cfn.Ref(lbFargateTaskMemoryKey),
+		ExecutionRoleArn:        cfn.GetAtt("ExecutionRole", "Arn"),
+		TaskRoleArn:             cfn.GetAtt("ExecutionRole", "Arn"),
+		ContainerDefinitions: []cfn.ContainerDefinition{
+			{
+				Name:      params.App.Name,
+				Image:     cfn.Ref(lbFargateParamContainerImageKey),
+				Essential: true,
+				PortMappings: []cfn.PortMapping{
+					{ContainerPort: cfn.Ref(lbFargateParamContainerPortKey)},
+				},
+				LogConfiguration: &cfn.LogConfiguration{
+					LogDriver: "awslogs",
+					Options: map[string]interface{}{
+						"awslogs-group":         cfn.Ref("LogGroup"),
+						"awslogs-region":        cfn.Ref("AWS::Region"),
+						"awslogs-stream-prefix": params.App.Name,
+					},
+				},
+			},
+		},
+	}))
+
+	tpl.AddResource("TargetGroup", cfn.NewTargetGroup(cfn.TargetGroupProperties{
+		Port:            cfn.Ref(lbFargateParamContainerPortKey),
+		Protocol:        "HTTP",
+		TargetType:      "ip",
+		VpcId:           cfn.ImportValue(cfn.Sub(fmt.Sprintf("${%s}-${%s}-VpcId", lbFargateParamProjectNameKey, lbFargateParamEnvNameKey))),
+		HealthCheckPath: "/",
+	}))
+
+	tpl.AddResource("ListenerRule", cfn.NewListenerRule(cfn.ListenerRuleProperties{
+		ListenerArn: cfn.ImportValue(cfn.Sub(fmt.Sprintf("${%s}-${%s}-HTTPListenerArn", lbFargateParamProjectNameKey, lbFargateParamEnvNameKey))),
+		Priority:    cfn.Ref(lbFargateRulePriorityKey),
+		Actions: []cfn.RuleAction{
+			{Type: "forward", TargetGroupArn: cfn.Ref("TargetGroup")},
+		},
+		Conditions: []cfn.RuleCondition{
+			{
+				Field:  "path-pattern",
+				Values: []interface{}{cfn.Sub(fmt.Sprintf("${%s}*", lbFargateRulePathKey))},
+			},
+		},
+	}))
+
+	if c.addonsTemplatePath != "" {
+		// The Local:// marker is resolved to an uploaded S3 object URL by
+		// PackageAppOpts.packageLocalArtifacts when --s3-bucket is set; archer app deploy resolves it
+		// the same way before creating or updating the stack.
+		tpl.AddResource("AddonsStack", cfn.NewNestedStack(cfn.NestedStackProperties{
+			TemplateURL: fmt.Sprintf("Local://%s", c.addonsTemplatePath),
+		}))
+	}
+
+	tpl.AddResource("Service", cfn.NewECSService(cfn.ECSServiceProperties{
+		Cluster:        cfn.ImportValue(cfn.Sub(fmt.Sprintf("${%s}-${%s}-ClusterId", lbFargateParamProjectNameKey, lbFargateParamEnvNameKey))),
+		ServiceName:    name,
+		TaskDefinition: cfn.Ref("TaskDefinition"),
+		DesiredCount:   cfn.Ref(lbFargateTaskCountKey),
+		LaunchType:     "FARGATE",
+		NetworkConfiguration: &cfn.NetworkConfiguration{
+			AwsVpcConfiguration: cfn.AwsVpcConfiguration{
+				Subnets:        cfn.Split(",", cfn.ImportValue(cfn.Sub(fmt.Sprintf("${%s}-${%s}-PrivateSubnets", lbFargateParamProjectNameKey, lbFargateParamEnvNameKey)))),
+				SecurityGroups: []interface{}{cfn.ImportValue(cfn.Sub(fmt.Sprintf("${%s}-${%s}-EnvironmentSecurityGroup", lbFargateParamProjectNameKey, lbFargateParamEnvNameKey)))},
+				AssignPublicIp: "DISABLED",
+			},
+		},
+		LoadBalancers: []cfn.LoadBalancer{
+			{
+				ContainerName:  params.App.Name,
+				ContainerPort:  cfn.Ref(lbFargateParamContainerPortKey),
+				TargetGroupArn: cfn.Ref("TargetGroup"),
+			},
+		},
+		DeploymentConfiguration: &cfn.DeploymentConfiguration{
+			MaximumPercent:        200,
+			MinimumHealthyPercent: 100,
+		},
+	}))
+
+	return tpl, nil
 }
 
-// Parameters returns the list of CloudFormation parameters used by the template.
-func (c *LBFargateStackConfig) Parameters() []*cloudformation.Parameter {
-	templateParams := c.toTemplateParams()
+// Parameters returns the list of CloudFormation parameters used by the template, with any
+// caller-supplied overrides applied.
+func (c *LBFargateStackConfig) Parameters() ([]*cloudformation.Parameter, error) {
+	templateParams, err := c.toTemplateParams()
+	if err != nil {
+		return nil, err
+	}
 	return []*cloudformation.Parameter{
 		{
 			ParameterKey:   aws.String(lbFargateParamProjectNameKey),
@@ -123,11 +311,11 @@ func (c *LBFargateStackConfig) Parameters() []*cloudformation.Parameter {
 			ParameterKey:   aws.String(lbFargateTaskCountKey),
 			ParameterValue: aws.String(strconv.Itoa(templateParams.App.Count)),
 		},
-	}
+	}, nil
 }
 
-// SerializedParameters returns the CloudFormation stack's parameters serialized
-// to a YAML document annotated with comments for readability to users.
+// SerializedParameters returns the CloudFormation stack's parameters, with any caller-supplied
+// overrides applied, serialized to a YAML document annotated with comments for readability to users.
 func (c *LBFargateStackConfig) SerializedParameters() (string, error) {
 	content, err := c.box.FindString(lbFargateAppParamsPath)
 	if err != nil {
@@ -137,17 +325,56 @@ func (c *LBFargateStackConfig) SerializedParameters() (string, error) {
 	if err != nil {
 		return "", fmt.Errorf("parse stack configuration for %s: %w", c.App.Type, err)
 	}
+	templateParams, err := c.toTemplateParams()
+	if err != nil {
+		return "", err
+	}
 	var buf bytes.Buffer
-	if err := tpl.Execute(&buf, c.toTemplateParams()); err != nil {
+	if err := tpl.Execute(&buf, templateParams); err != nil {
 		return "", fmt.Errorf("execute stack configuration for %s: %w", c.App.Type, err)
 	}
-	return buf.String(), nil
-	return "", nil
+	tagsYAML, err := c.serializedTags()
+	if err != nil {
+		return "", err
+	}
+	content = buf.String()
+	if tagsYAML == "" {
+		return content, nil
+	}
+	// content comes from executing the packd-templated params.json, which isn't guaranteed to
+	// end in a newline; without one, appending the Tags section would glue it onto content's
+	// last line and produce invalid YAML.
+	if !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	return content + tagsYAML, nil
 }
 
-// Tags returns the list of tags to apply to the CloudFormation stack.
+// serializedTags renders the stack's tags (see Tags) as a "Tags:" YAML section, so that the
+// project/env/app tags and any --tags overrides are reflected in the package command's output
+// artifacts rather than only being applied at deploy time. Returns "" if there are no tags.
+func (c *LBFargateStackConfig) serializedTags() (string, error) {
+	tags := c.Tags()
+	if len(tags) == 0 {
+		return "", nil
+	}
+	byKey := make(map[string]string, len(tags))
+	for _, tag := range tags {
+		byKey[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+	}
+	out, err := yaml.Marshal(struct {
+		Tags map[string]string `yaml:"Tags"`
+	}{Tags: byKey})
+	if err != nil {
+		return "", fmt.Errorf("marshal stack tags for %s: %w", c.App.Type, err)
+	}
+	return string(out), nil
+}
+
+// Tags returns the list of tags to apply to the CloudFormation stack: the default project/env/app
+// tags, followed by any caller-supplied extra tags in sorted key order.
 func (c *LBFargateStackConfig) Tags() []*cloudformation.Tag {
-	return []*cloudformation.Tag{
+	tags := []*cloudformation.Tag{
 		{
 			Key:   aws.String(projectTagKey),
 			Value: aws.String(c.Env.Project),
@@ -161,6 +388,18 @@ func (c *LBFargateStackConfig) Tags() []*cloudformation.Tag {
 			Value: aws.String(c.App.Name),
 		},
 	}
+	keys := make([]string, 0, len(c.extraTags))
+	for k := range c.extraTags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		tags = append(tags, &cloudformation.Tag{
+			Key:   aws.String(k),
+			Value: aws.String(c.extraTags[k]),
+		})
+	}
+	return tags
 }
 
 // lbFargateTemplateParams holds the data to render the CloudFormation template for an application.
@@ -177,18 +416,23 @@ type lbFargateTemplateParams struct {
 	}
 }
 
-func (c *LBFargateStackConfig) toTemplateParams() *lbFargateTemplateParams {
+func (c *LBFargateStackConfig) toTemplateParams() (*lbFargateTemplateParams, error) {
 	imgLoc := fmt.Sprintf("%s/%s/%s:%s", c.Env.Project, c.Env.Name, c.App.Name, c.ImageTag)
 	url := fmt.Sprintf(ecrURLFormatString, c.Env.AccountID, c.Env.Region, imgLoc)
-	return &lbFargateTemplateParams{
+	appConf := c.CreateLBFargateAppInput.App.EnvConf(c.Env.Name) // Get environment specific app configuration.
+	priority, err := c.priority(appConf.Path)
+	if err != nil {
+		return nil, err
+	}
+	params := &lbFargateTemplateParams{
 		CreateLBFargateAppInput: &deploy.CreateLBFargateAppInput{
 			App: &manifest.LBFargateManifest{
 				AppManifest:     c.App.AppManifest,
-				LBFargateConfig: c.CreateLBFargateAppInput.App.EnvConf(c.Env.Name), // Get environment specific app configuration.
+				LBFargateConfig: appConf,
 			},
 			Env: c.Env,
 		},
-		Priority: 1, // TODO assign a unique path priority given a path.
+		Priority: priority,
 		Image: struct {
 			URL  string
 			Port int
@@ -197,4 +441,66 @@ func (c *LBFargateStackConfig) toTemplateParams() *lbFargateTemplateParams {
 			Port: c.App.Image.Port,
 		},
 	}
+	if err := c.applyOverrides(params); err != nil {
+		return nil, err
+	}
+	return params, nil
+}
+
+// priority returns the ALB listener rule priority to assign to the application's routing rule on
+// path, using the configured PriorityAllocator (see WithPriorityAllocator) or, absent one, a
+// HashPriorityAllocator so the rendered template stays deterministic.
+func (c *LBFargateStackConfig) priority(path string) (int, error) {
+	allocator := c.priorityAllocator
+	if allocator == nil {
+		allocator = HashPriorityAllocator{}
+	}
+	return allocator.RulePriority(c.listenerArn, c.Env.Project, c.Env.Name, c.App.Name, path)
+}
+
+// applyOverrides merges any caller-supplied --parameter-overrides into params, rejecting
+// overrides for reserved keys or keys that aren't valid template parameters.
+func (c *LBFargateStackConfig) applyOverrides(params *lbFargateTemplateParams) error {
+	for key, value := range c.overrides {
+		if reservedLBFargateParamKeys[key] {
+			return fmt.Errorf("parameter %q is derived from the application and environment and cannot be overridden", key)
+		}
+		switch key {
+		case lbFargateParamContainerPortKey:
+			port, err := strconv.Atoi(value)
+			if err != nil {
+				return fmt.Errorf("parse override for %s as an integer: %w", key, err)
+			}
+			params.Image.Port = port
+		case lbFargateRulePriorityKey:
+			priority, err := strconv.Atoi(value)
+			if err != nil {
+				return fmt.Errorf("parse override for %s as an integer: %w", key, err)
+			}
+			params.Priority = priority
+		case lbFargateRulePathKey:
+			params.App.Path = value
+		case lbFargateTaskCPUKey:
+			cpu, err := strconv.Atoi(value)
+			if err != nil {
+				return fmt.Errorf("parse override for %s as an integer: %w", key, err)
+			}
+			params.App.CPU = cpu
+		case lbFargateTaskMemoryKey:
+			memory, err := strconv.Atoi(value)
+			if err != nil {
+				return fmt.Errorf("parse override for %s as an integer: %w", key, err)
+			}
+			params.App.Memory = memory
+		case lbFargateTaskCountKey:
+			count, err := strconv.Atoi(value)
+			if err != nil {
+				return fmt.Errorf("parse override for %s as an integer: %w", key, err)
+			}
+			params.App.Count = count
+		default:
+			return fmt.Errorf("parameter %q is not defined in the %s template", key, c.App.Type)
+		}
+	}
+	return nil
 }
\ No newline at end of file
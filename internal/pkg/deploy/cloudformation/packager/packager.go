@@ -0,0 +1,187 @@
+// Copyright 2019 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package packager uploads local file artifacts referenced by a rendered CloudFormation
+// template to S3 and rewrites the references to the resulting s3://bucket/key locations,
+// analogous to what `aws cloudformation package`/`sam package` do for SAM templates.
+package packager
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+)
+
+// localRefPrefix marks a template value as a path to a local file rather than an inline
+// value, e.g. "Local://sidecars/collector.zip". Package replaces these markers with the
+// uploaded artifact's S3 URL.
+const localRefPrefix = "Local://"
+
+// Artifact is a single local file that was uploaded to S3 as part of packaging.
+type Artifact struct {
+	// LocalPath is the file's path as it appeared in the template, relative to workDir.
+	LocalPath string
+	// Bucket is the S3 bucket the artifact was uploaded to.
+	Bucket string
+	// Key is the content-addressed S3 object key for the artifact.
+	Key string
+	// URL is the s3://bucket/key reference that replaced LocalPath in the rewritten template.
+	URL string
+}
+
+// Packager uploads the local file artifacts referenced by a rendered CloudFormation template
+// and returns a copy of the template with those references rewritten to their uploaded,
+// content-addressed S3 locations.
+type Packager interface {
+	// Package uploads any local file artifacts referenced by template (resolved relative to
+	// workDir) and returns the rewritten template along with the artifacts it uploaded.
+	Package(ctx context.Context, template, workDir string) (rewrittenTemplate string, artifacts []Artifact, err error)
+}
+
+// S3Packager uploads local artifacts to a single, per-project S3 bucket.
+type S3Packager struct {
+	Bucket string
+	Prefix string
+
+	S3 s3iface.S3API
+}
+
+// NewS3Packager returns an S3Packager that uploads artifacts to bucket, under prefix.
+func NewS3Packager(s3Client s3iface.S3API, bucket, prefix string) *S3Packager {
+	return &S3Packager{
+		Bucket: bucket,
+		Prefix: prefix,
+		S3:     s3Client,
+	}
+}
+
+// Package finds every "Local://" reference in template, uploads the file it points to
+// (resolved relative to workDir) to the packager's S3 bucket under a content-addressed key,
+// and returns the template with each reference replaced by its s3://bucket/key URL. Uploads
+// are skipped if an object already exists under the computed key.
+func (p *S3Packager) Package(ctx context.Context, template, workDir string) (string, []Artifact, error) {
+	rewritten := template
+	var artifacts []Artifact
+
+	for _, ref := range localRefs(template) {
+		artifact, err := p.upload(ctx, ref.path, workDir)
+		if err != nil {
+			return "", nil, fmt.Errorf("package local artifact %s: %w", ref.path, err)
+		}
+		rewritten = strings.ReplaceAll(rewritten, ref.raw, artifact.URL)
+		artifacts = append(artifacts, artifact)
+	}
+	return rewritten, artifacts, nil
+}
+
+// localRefPattern matches a "Local://" marker and the local file path that follows it. YAML
+// quotes a scalar in single or double quotes when it contains characters like spaces that
+// wouldn't otherwise be valid in a plain scalar, so a quoted path may contain whitespace; an
+// unquoted path runs up to the next whitespace.
+var localRefPattern = regexp.MustCompile(`'` + localRefPrefix + `([^']*)'|"` + localRefPrefix + `([^"]*)"|` + localRefPrefix + `(\S+)`)
+
+// localRef is a single "Local://" marker found in a rendered template.
+type localRef struct {
+	raw  string // raw is the exact substring matched in the template, including any surrounding quotes.
+	path string // path is the local file path the marker references, with quotes removed.
+}
+
+// localRefs returns the unique local file paths referenced in template via "Local://" markers,
+// in the order they first appear. A template may reference more than one local file per line.
+func localRefs(template string) []localRef {
+	seen := make(map[string]bool)
+	var refs []localRef
+	for _, match := range localRefPattern.FindAllStringSubmatch(template, -1) {
+		path := match[1] + match[2] + match[3] // Exactly one of these three groups is non-empty.
+		if seen[path] {
+			continue
+		}
+		seen[path] = true
+		refs = append(refs, localRef{raw: match[0], path: path})
+	}
+	return refs
+}
+
+// upload uploads the file at filepath.Join(workDir, localPath) to S3 under a sha256
+// content-addressed key, skipping the upload if an object with that key already exists.
+func (p *S3Packager) upload(ctx context.Context, localPath, workDir string) (Artifact, error) {
+	fullPath := filepath.Join(workDir, localPath)
+	f, err := os.Open(fullPath)
+	if err != nil {
+		return Artifact{}, fmt.Errorf("open %s: %w", fullPath, err)
+	}
+	defer f.Close()
+
+	sum, err := sha256sum(f)
+	if err != nil {
+		return Artifact{}, fmt.Errorf("checksum %s: %w", fullPath, err)
+	}
+	key := p.key(localPath, sum)
+
+	exists, err := p.exists(ctx, key)
+	if err != nil {
+		return Artifact{}, err
+	}
+	if !exists {
+		if _, err := f.Seek(0, io.SeekStart); err != nil {
+			return Artifact{}, fmt.Errorf("seek %s: %w", fullPath, err)
+		}
+		if _, err := p.S3.PutObjectWithContext(ctx, &s3.PutObjectInput{
+			Bucket: aws.String(p.Bucket),
+			Key:    aws.String(key),
+			Body:   f,
+		}); err != nil {
+			return Artifact{}, fmt.Errorf("upload %s to s3://%s/%s: %w", fullPath, p.Bucket, key, err)
+		}
+	}
+	return Artifact{
+		LocalPath: localPath,
+		Bucket:    p.Bucket,
+		Key:       key,
+		URL:       fmt.Sprintf("s3://%s/%s", p.Bucket, key),
+	}, nil
+}
+
+// exists returns true if an object already exists in the bucket under key.
+func (p *S3Packager) exists(ctx context.Context, key string) (bool, error) {
+	_, err := p.S3.HeadObjectWithContext(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(p.Bucket),
+		Key:    aws.String(key),
+	})
+	if err == nil {
+		return true, nil
+	}
+	if aerr, ok := err.(awserr.Error); ok && aerr.Code() == "NotFound" {
+		return false, nil
+	}
+	return false, fmt.Errorf("head s3://%s/%s: %w", p.Bucket, key, err)
+}
+
+// key returns the content-addressed S3 object key for localPath given the sha256 sum of its contents.
+func (p *S3Packager) key(localPath string, sum []byte) string {
+	name := filepath.Base(localPath)
+	hash := hex.EncodeToString(sum)
+	if p.Prefix == "" {
+		return fmt.Sprintf("%s/%s", hash, name)
+	}
+	return fmt.Sprintf("%s/%s/%s", p.Prefix, hash, name)
+}
+
+func sha256sum(r io.Reader) ([]byte, error) {
+	h := sha256.New()
+	if _, err := io.Copy(h, r); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
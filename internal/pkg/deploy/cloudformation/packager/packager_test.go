@@ -0,0 +1,61 @@
+// Copyright 2019 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package packager
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLocalRefs(t *testing.T) {
+	testCases := map[string]struct {
+		template string
+		want     []localRef
+	}{
+		"no local refs": {
+			template: "TemplateURL: s3://my-bucket/key\n",
+			want:     nil,
+		},
+		"single unquoted ref": {
+			template: "TemplateURL: Local://addons/storage.yml\n",
+			want: []localRef{
+				{raw: "Local://addons/storage.yml", path: "addons/storage.yml"},
+			},
+		},
+		"single-quoted ref with a space in the path": {
+			template: "TemplateURL: 'Local://addons/my storage.yml'\n",
+			want: []localRef{
+				{raw: "'Local://addons/my storage.yml'", path: "addons/my storage.yml"},
+			},
+		},
+		"double-quoted ref with a space in the path": {
+			template: `TemplateURL: "Local://addons/my storage.yml"` + "\n",
+			want: []localRef{
+				{raw: `"Local://addons/my storage.yml"`, path: "addons/my storage.yml"},
+			},
+		},
+		"multiple refs on the same line": {
+			template: "Values: [Local://a.yml, Local://b.yml]\n",
+			want: []localRef{
+				{raw: "Local://a.yml,", path: "a.yml,"},
+				{raw: "Local://b.yml]", path: "b.yml]"},
+			},
+		},
+		"duplicate refs are returned once, in first-seen order": {
+			template: "A: Local://shared.yml\nB: Local://shared.yml\n",
+			want: []localRef{
+				{raw: "Local://shared.yml", path: "shared.yml"},
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got := localRefs(tc.template)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("localRefs(%q) = %#v, want %#v", tc.template, got, tc.want)
+			}
+		})
+	}
+}
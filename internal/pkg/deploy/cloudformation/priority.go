@@ -0,0 +1,155 @@
+// Copyright 2019 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package cloudformation
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/aws/aws-sdk-go/service/elbv2/elbv2iface"
+)
+
+// ALB listener rules accept priorities in [1, 50000]; lower numbers are evaluated first.
+// https://docs.aws.amazon.com/elasticloadbalancing/latest/application/load-balancer-listeners.html#listener-rules
+const (
+	minListenerRulePriority  = 1
+	maxListenerRulePriority  = 50000
+	listenerRulePrioritySpan = maxListenerRulePriority - minListenerRulePriority + 1
+)
+
+// PriorityAllocator assigns the ALB listener rule priority for an application's routing rule.
+type PriorityAllocator interface {
+	// RulePriority returns the listener rule priority to use for app's routing rule on path,
+	// on the project/env's shared listener identified by listenerArn.
+	RulePriority(listenerArn, project, env, app, path string) (int, error)
+}
+
+// HashPriorityAllocator deterministically derives a priority from the hash of
+// (project, env, app, path) without inspecting what's already allocated on the listener. It's
+// the default used by `archer app package`, which may run without AWS credentials, so that
+// packaging the same application twice always produces the same, reproducible priority.
+type HashPriorityAllocator struct{}
+
+// RulePriority returns the hash-derived priority for app's routing rule; listenerArn is ignored.
+func (HashPriorityAllocator) RulePriority(listenerArn, project, env, app, path string) (int, error) {
+	return hashPriority(project, env, app, path), nil
+}
+
+// hashPriority hashes (project, env, app, path) to a stable priority in [1, 50000].
+func hashPriority(project, env, app, path string) int {
+	h := fnv.New32a()
+	fmt.Fprintf(h, "%s/%s/%s%s", project, env, app, path)
+	return minListenerRulePriority + int(h.Sum32()%uint32(listenerRulePrioritySpan))
+}
+
+// ListenerRulePriorityAllocator assigns a priority by probing the project/env's shared HTTP(S)
+// listener for a free slot, preferring the hash-derived slot so re-packages of the same
+// application land on the same priority whenever it's still free. Used by `archer app deploy`,
+// which always has the AWS credentials needed to describe the listener's existing rules.
+type ListenerRulePriorityAllocator struct {
+	ELBV2 elbv2iface.ELBV2API
+}
+
+// NewListenerRulePriorityAllocator returns a ListenerRulePriorityAllocator backed by elbv2Client.
+func NewListenerRulePriorityAllocator(elbv2Client elbv2iface.ELBV2API) *ListenerRulePriorityAllocator {
+	return &ListenerRulePriorityAllocator{ELBV2: elbv2Client}
+}
+
+// existingRule is the part of an already-deployed listener rule RulePriority needs: the value of
+// its longest path-pattern condition. It's used both to recognize a slot as already belonging to
+// this application's own rule from a previous deploy, and, for a genuine collision with another
+// application's rule, to tie-break against it.
+type existingRule struct {
+	pathPattern string
+}
+
+// RulePriority returns a free listener rule priority for app's routing rule on path. It prefers
+// the hash-derived slot. If that slot is occupied by this application's own rule from a previous
+// deploy (recognized by its path-pattern condition matching path), the same priority is reused so
+// redeploys of an unchanged app/path don't churn. Otherwise, if it's a genuine collision with
+// another application's rule, it compares path against the occupying rule's actual path-pattern
+// condition and probes toward the lower, higher-precedence priority numbers when path is the more
+// specific (longer) of the two, or toward the higher numbers otherwise — so the more specific path
+// is the one ALB evaluates first, regardless of which application happened to deploy first.
+func (a *ListenerRulePriorityAllocator) RulePriority(listenerArn, project, env, app, path string) (int, error) {
+	taken, err := a.existingPriorities(listenerArn)
+	if err != nil {
+		return 0, err
+	}
+	start := hashPriority(project, env, app, path)
+	ownPathPattern := path + "*" // Matches the "${RulePath}*" condition toCloudFormationTemplate renders.
+	if occupant, collides := taken[start]; !collides || occupant.pathPattern == ownPathPattern {
+		return start, nil
+	} else if len(path) > len(strings.TrimSuffix(occupant.pathPattern, "*")) {
+		return a.probe(listenerArn, taken, start, -1, ownPathPattern)
+	}
+	return a.probe(listenerArn, taken, start, 1, ownPathPattern)
+}
+
+// probe walks the valid priority range starting from start (exclusive) in the given direction
+// (+1 or -1, wrapping around), returning the first slot that's free or already held by
+// ownPathPattern's own rule.
+func (a *ListenerRulePriorityAllocator) probe(listenerArn string, taken map[int]existingRule, start, step int, ownPathPattern string) (int, error) {
+	for offset := 1; offset <= listenerRulePrioritySpan; offset++ {
+		candidate := wrapPriority(start + offset*step)
+		if occupant, collides := taken[candidate]; !collides || occupant.pathPattern == ownPathPattern {
+			return candidate, nil
+		}
+	}
+	return 0, fmt.Errorf("no free ALB listener rule priority available on listener %s", listenerArn)
+}
+
+// wrapPriority wraps p into the valid [minListenerRulePriority, maxListenerRulePriority] range.
+func wrapPriority(p int) int {
+	p = ((p - minListenerRulePriority) % listenerRulePrioritySpan) + minListenerRulePriority
+	if p < minListenerRulePriority {
+		p += listenerRulePrioritySpan
+	}
+	return p
+}
+
+// pathPatternConditionField is the elbv2.RuleCondition.Field value for a path-based routing rule.
+const pathPatternConditionField = "path-pattern"
+
+// existingPriorities returns, for every priority already in use on listenerArn, the longest
+// path-pattern condition value on the rule occupying it.
+func (a *ListenerRulePriorityAllocator) existingPriorities(listenerArn string) (map[int]existingRule, error) {
+	taken := make(map[int]existingRule)
+	in := &elbv2.DescribeRulesInput{ListenerArn: aws.String(listenerArn)}
+	err := a.ELBV2.DescribeRulesPages(in, func(page *elbv2.DescribeRulesOutput, lastPage bool) bool {
+		for _, rule := range page.Rules {
+			priority, err := strconv.Atoi(aws.StringValue(rule.Priority))
+			if err != nil {
+				continue // The "default" rule has a non-numeric priority; every other rule's is numeric.
+			}
+			taken[priority] = existingRule{pathPattern: rulePathPattern(rule)}
+		}
+		return true
+	})
+	if err != nil {
+		return nil, fmt.Errorf("describe rules for listener %s: %w", listenerArn, err)
+	}
+	return taken, nil
+}
+
+// rulePathPattern returns the longest path-pattern condition value on rule, or "" if rule has no
+// path-pattern condition (e.g. a host-based rule).
+func rulePathPattern(rule *elbv2.Rule) string {
+	longest := ""
+	for _, cond := range rule.Conditions {
+		if aws.StringValue(cond.Field) != pathPatternConditionField {
+			continue
+		}
+		for _, value := range cond.Values {
+			if v := aws.StringValue(value); len(v) > len(longest) {
+				longest = v
+			}
+		}
+	}
+	return longest
+}
@@ -0,0 +1,121 @@
+// Copyright 2019 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package cloudformation
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/aws/aws-sdk-go/service/elbv2/elbv2iface"
+)
+
+// stubELBV2 embeds the ELBV2API interface so it only needs to implement the single method
+// ListenerRulePriorityAllocator actually calls; every other method panics if exercised.
+type stubELBV2 struct {
+	elbv2iface.ELBV2API
+	rules []*elbv2.Rule
+}
+
+func (s *stubELBV2) DescribeRulesPages(in *elbv2.DescribeRulesInput, fn func(*elbv2.DescribeRulesOutput, bool) bool) error {
+	fn(&elbv2.DescribeRulesOutput{Rules: s.rules}, true)
+	return nil
+}
+
+func pathPatternRule(priority int, path string) *elbv2.Rule {
+	return &elbv2.Rule{
+		Priority: aws.String(fmt.Sprint(priority)),
+		Conditions: []*elbv2.RuleCondition{
+			{
+				Field:  aws.String(pathPatternConditionField),
+				Values: []*string{aws.String(path)},
+			},
+		},
+	}
+}
+
+func TestHashPriorityAllocator_RulePriority(t *testing.T) {
+	alloc := HashPriorityAllocator{}
+	got1, err := alloc.RulePriority("arn:listener", "my-project", "test", "my-app", "/svc")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got2, err := alloc.RulePriority("arn:listener", "my-project", "test", "my-app", "/svc")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got1 != got2 {
+		t.Errorf("expected repeated calls with the same inputs to return the same priority, got %d and %d", got1, got2)
+	}
+	if got1 < minListenerRulePriority || got1 > maxListenerRulePriority {
+		t.Errorf("priority %d out of valid range [%d, %d]", got1, minListenerRulePriority, maxListenerRulePriority)
+	}
+}
+
+func TestListenerRulePriorityAllocator_RulePriority(t *testing.T) {
+	const (
+		project = "my-project"
+		env     = "test"
+		app     = "my-app"
+		path    = "/svc"
+	)
+	start := hashPriority(project, env, app, path)
+	ownPathPattern := path + "*"
+
+	t.Run("an empty listener gets the hash-derived priority", func(t *testing.T) {
+		a := &ListenerRulePriorityAllocator{ELBV2: &stubELBV2{}}
+		got, err := a.RulePriority("arn:listener", project, env, app, path)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got != start {
+			t.Errorf("got %d, want hash-derived priority %d", got, start)
+		}
+	})
+
+	t.Run("redeploying the same app/path reuses its own existing priority instead of churning", func(t *testing.T) {
+		a := &ListenerRulePriorityAllocator{ELBV2: &stubELBV2{
+			rules: []*elbv2.Rule{pathPatternRule(start, ownPathPattern)},
+		}}
+		got, err := a.RulePriority("arn:listener", project, env, app, path)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got != start {
+			t.Errorf("got %d, want the unchanged priority %d (redeploy should not move it)", got, start)
+		}
+	})
+
+	t.Run("a collision with another app's longer path probes toward higher priority numbers", func(t *testing.T) {
+		occupantPath := ownPathPattern + "/longer-and-more-specific*"
+		a := &ListenerRulePriorityAllocator{ELBV2: &stubELBV2{
+			rules: []*elbv2.Rule{pathPatternRule(start, occupantPath)},
+		}}
+		got, err := a.RulePriority("arn:listener", project, env, app, path)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got == start {
+			t.Errorf("expected a genuine collision to move off %d, got the same priority back", start)
+		}
+		if got != wrapPriority(start+1) {
+			t.Errorf("got %d, want probe to land on %d (the less-specific rule yields to the more specific occupant)", got, wrapPriority(start+1))
+		}
+	})
+
+	t.Run("a collision with another app's shorter path probes toward lower priority numbers", func(t *testing.T) {
+		occupantPath := "/a*"
+		a := &ListenerRulePriorityAllocator{ELBV2: &stubELBV2{
+			rules: []*elbv2.Rule{pathPatternRule(start, occupantPath)},
+		}}
+		got, err := a.RulePriority("arn:listener", project, env, app, path)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got != wrapPriority(start-1) {
+			t.Errorf("got %d, want probe to land on %d (the more specific path wins the hash slot's precedence)", got, wrapPriority(start-1))
+		}
+	})
+}
@@ -0,0 +1,64 @@
+// Copyright 2019 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package cloudformation
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/aws/amazon-ecs-cli-v2/internal/pkg/archer"
+	"github.com/aws/amazon-ecs-cli-v2/internal/pkg/deploy"
+	"github.com/aws/amazon-ecs-cli-v2/internal/pkg/manifest"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+)
+
+// StackRenderer is satisfied by a stack config, such as LBFargateStackConfig, that can render
+// itself into a deployable CloudFormation stack. Callers that only need to package or deploy a
+// stack should depend on this interface rather than a concrete *XStackConfig type, so that new
+// workload kinds can be added without those callers changing.
+type StackRenderer interface {
+	StackName() string
+	Template() (string, error)
+	SerializedParameters() (string, error)
+	Parameters() ([]*cloudformation.Parameter, error)
+	Tags() []*cloudformation.Tag
+}
+
+// RendererFactory builds a StackRenderer for a manifest of the single concrete type it was
+// registered for under RegisterRenderer. mft is guaranteed to hold that concrete type.
+// addonsTemplatePath is the path, relative to the workspace root, to an optional CloudFormation
+// template to deploy alongside the app as a nested stack; it's empty when the app has no addons.
+type RendererFactory func(mft manifest.Manifest, env *archer.Environment, imageTag string, overrides, extraTags map[string]string, addonsTemplatePath string) (StackRenderer, error)
+
+// renderers maps a manifest's concrete type to the factory that builds its StackRenderer.
+var renderers = make(map[reflect.Type]RendererFactory)
+
+// RegisterRenderer associates factory with manifests whose concrete type matches mft, so that
+// RenderStack can build a StackRenderer for it without needing to know the type exists. Out-of-
+// tree workload kinds (for example a Backend Fargate service that skips the ALB, or a Step
+// Functions-based workflow renderer) can call this from their own package's init() instead of
+// editing this package or its callers.
+func RegisterRenderer(mft manifest.Manifest, factory RendererFactory) {
+	renderers[reflect.TypeOf(mft)] = factory
+}
+
+// RenderStack looks up the StackRenderer factory registered for mft's concrete type and invokes
+// it for env, imageTag, the given parameter overrides and extra tags, and addonsTemplatePath.
+func RenderStack(mft manifest.Manifest, env *archer.Environment, imageTag string, overrides, extraTags map[string]string, addonsTemplatePath string) (StackRenderer, error) {
+	factory, ok := renderers[reflect.TypeOf(mft)]
+	if !ok {
+		return nil, fmt.Errorf("no CloudFormation stack renderer registered for manifest of type %T", mft)
+	}
+	return factory(mft, env, imageTag, overrides, extraTags, addonsTemplatePath)
+}
+
+func init() {
+	RegisterRenderer(&manifest.LBFargateManifest{}, func(mft manifest.Manifest, env *archer.Environment, imageTag string, overrides, extraTags map[string]string, addonsTemplatePath string) (StackRenderer, error) {
+		return NewLBFargateStack(&deploy.CreateLBFargateAppInput{
+			App:      mft.(*manifest.LBFargateManifest),
+			Env:      env,
+			ImageTag: imageTag,
+		}, WithParameterOverrides(overrides), WithExtraTags(extraTags), WithAddonsTemplatePath(addonsTemplatePath)), nil
+	})
+}